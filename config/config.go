@@ -0,0 +1,82 @@
+// Package config holds the tunables shared by initialization, proving, and
+// verifying: the proof-of-space parameters that must match across all three
+// for a given data directory.
+package config
+
+// Config holds the parameters of a PoST instance that must be agreed upon
+// by the initializer, the prover, and every verifier.
+type Config struct {
+	// BitsPerLabel is the size, in bits, of a single label.
+	BitsPerLabel uint
+
+	// LabelsPerUnit is the number of labels in a single space unit.
+	LabelsPerUnit uint64
+
+	// MinNumUnits and MaxNumUnits bound how many space units a data
+	// directory may be initialized with.
+	MinNumUnits uint
+	MaxNumUnits uint
+
+	// K1 is the difficulty parameter: a label qualifies for inclusion in a
+	// proof if its hash is at most MaxTarget * K1 / NumLabels.
+	K1 uint32
+
+	// K2 is the number of qualifying labels a valid proof must include.
+	K2 uint32
+
+	// VDFEnabled gates label selection behind a Wesolowski VDF: instead of
+	// selecting under the raw challenge ch, GenerateProof selects under
+	// ch' = VDF(ch, VDFIterations), and embeds the VDF proof in
+	// ProofMetaData so verifying.Verify can check it before doing any
+	// Merkle work. This raises the cost of grinding challenges to bias
+	// which labels get selected, at the cost of VDFIterations sequential
+	// class-group squarings per proof.
+	VDFEnabled bool
+
+	// VDFIterations is T, the number of sequential squarings the VDF
+	// stage performs. Only consulted when VDFEnabled.
+	VDFIterations uint64
+
+	// VDFDiscriminantBits is the bit length of the class group
+	// discriminant the VDF stage operates in. Only consulted when
+	// VDFEnabled.
+	VDFDiscriminantBits int
+}
+
+// DefaultConfig returns the recommended Config for production use.
+func DefaultConfig() Config {
+	return Config{
+		BitsPerLabel:  32,
+		LabelsPerUnit: 1 << 20,
+		MinNumUnits:   1,
+		MaxNumUnits:   1000,
+		K1:            2000,
+		K2:            200,
+	}
+}
+
+// InitOpts holds the parameters of a single Initialize call.
+type InitOpts struct {
+	// DataDir is the directory the label files and metadata are written
+	// to and read from.
+	DataDir string
+
+	// NumUnits is the number of space units to initialize, between
+	// Config.MinNumUnits and Config.MaxNumUnits.
+	NumUnits uint
+
+	// NumFiles is the number of files the label data is split across.
+	NumFiles uint
+
+	// ComputeProviderID identifies which compute provider (CPU, GPU, ...)
+	// performs initialization.
+	ComputeProviderID uint32
+}
+
+// DefaultInitOpts returns the recommended InitOpts for production use.
+func DefaultInitOpts() InitOpts {
+	return InitOpts{
+		NumUnits: 1,
+		NumFiles: 1,
+	}
+}
@@ -0,0 +1,283 @@
+// Package fraud implements compact, independently checkable fraud proofs
+// for PoST proofs that a verifier has found to be invalid.
+//
+// A verifier that runs the full verifying.Verify pass already knows which
+// label index disagreed with what the prover committed to. Rather than
+// forcing every downstream party to re-read the full PoST data file to
+// reach the same conclusion, the verifier can package that single
+// disagreement - the offending index, the prover's claimed label, and a
+// Merkle authentication path tying it back to the commitment - into a
+// Proof that anyone can check in O(log N).
+package fraud
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spacemeshos/post/datatypes"
+	"github.com/spacemeshos/post/shared"
+)
+
+// Reason identifies why a label index is being disputed.
+type Reason uint8
+
+const (
+	// BadLabel means the label the prover included for Index does not match
+	// the label the challenger independently recomputed under the
+	// commitment and challenge.
+	BadLabel Reason = iota + 1
+
+	// BadIndex means the label at Index hashes to a value that fails the
+	// K1/NumLabels difficulty requirement, so it should never have been
+	// selected for inclusion in the proof.
+	BadIndex
+)
+
+func (r Reason) String() string {
+	switch r {
+	case BadLabel:
+		return "bad-label"
+	case BadIndex:
+		return "bad-index"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(r))
+	}
+}
+
+// Proof is a compact, third-party-verifiable claim that a single label
+// index within a submitted PoST proof is invalid.
+type Proof struct {
+	Commitment []byte
+	Challenge  []byte
+	Reason     Reason
+
+	// Index is the position of the disputed label among all labels
+	// committed to by the prover.
+	Index uint64
+
+	// ClaimedLabel is the label the prover included in the proof at Index.
+	ClaimedLabel datatypes.Label
+
+	// ExpectedLabel is the label the challenger independently recomputed
+	// for Index. It is only populated for a BadLabel proof.
+	ExpectedLabel datatypes.Label
+
+	// MerklePath and MerklePathIndices are ClaimedLabel's Merkle
+	// authentication path up to Root: MerklePath[i] is the sibling hash at
+	// level i, and MerklePathIndices[i] is 1 if ClaimedLabel's hash is the
+	// left operand at that level and 0 if it's the right operand. This is
+	// the same convention verifying.Verify uses, so a verifier can build a
+	// Proof directly from the (path, pathIndex) pair it already computed
+	// while checking a PoST proof, with no conversion step.
+	MerklePath        [][]byte
+	MerklePathIndices []int64
+
+	// Root is the Merkle root the prover committed to for this proof.
+	Root []byte
+
+	// K1 and NumLabels are the difficulty parameters ClaimedLabel was
+	// selected under. They're only consulted for a BadIndex proof, to
+	// confirm ClaimedLabel actually fails the difficulty requirement
+	// rather than just trusting the accusation.
+	K1        uint32
+	NumLabels uint64
+}
+
+// ErrRootMismatch is returned by Verify when the authentication path does
+// not reconstruct Root.
+var ErrRootMismatch = errors.New("fraud: authentication path does not reach the claimed root")
+
+// ErrLabelsMatch is returned by Verify for a BadLabel proof whose claimed
+// and expected labels are equal, i.e. there is nothing to dispute.
+var ErrLabelsMatch = errors.New("fraud: claimed and expected labels are equal")
+
+// ErrIndexPasses is returned by Verify for a BadIndex proof whose claimed
+// label in fact satisfies the K1/NumLabels difficulty requirement, i.e.
+// there is nothing to dispute.
+var ErrIndexPasses = errors.New("fraud: claimed label satisfies the K1/NumLabels difficulty requirement")
+
+// Verify checks fp without needing access to the full PoST data file: it
+// recomputes the Merkle root from ClaimedLabel and MerklePath and confirms
+// it matches Root, then checks the claim itself depending on fp.Reason.
+func Verify(fp *Proof) (bool, error) {
+	root, err := recomputeRoot(fp.ClaimedLabel, fp.MerklePath, fp.MerklePathIndices)
+	if err != nil {
+		return false, err
+	}
+	if !bytes.Equal(root, fp.Root) {
+		return false, ErrRootMismatch
+	}
+
+	switch fp.Reason {
+	case BadLabel:
+		if bytes.Equal(fp.ClaimedLabel, fp.ExpectedLabel) {
+			return false, ErrLabelsMatch
+		}
+		return true, nil
+	case BadIndex:
+		if shared.IndexHash(fp.ClaimedLabel, fp.Challenge) <= shared.Difficulty(fp.K1, fp.NumLabels) {
+			return false, ErrIndexPasses
+		}
+		return true, nil
+	default:
+		return false, fmt.Errorf("fraud: unknown reason %v", fp.Reason)
+	}
+}
+
+// recomputeRoot walks path from the leaf for label up to the root,
+// combining with each sibling in the order dictated by pathIndex, matching
+// github.com/cbergoon/merkletree.GetMerklePath's convention.
+func recomputeRoot(label datatypes.Label, path [][]byte, pathIndex []int64) ([]byte, error) {
+	if len(path) != len(pathIndex) {
+		return nil, fmt.Errorf("fraud: merkle path has %d siblings but %d indices", len(path), len(pathIndex))
+	}
+
+	hash, err := label.CalculateHash()
+	if err != nil {
+		return nil, fmt.Errorf("fraud: hashing claimed label: %w", err)
+	}
+
+	for i, sibling := range path {
+		if pathIndex[i] == 1 {
+			hash, err = datatypes.Label(append(append([]byte{}, hash...), sibling...)).CalculateHash()
+		} else {
+			hash, err = datatypes.Label(append(append([]byte{}, sibling...), hash...)).CalculateHash()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("fraud: combining with sibling %d: %w", i, err)
+		}
+	}
+	return hash, nil
+}
+
+// wire format: a flat, fixed-order binary encoding. Every []byte field is
+// length-prefixed with a little-endian uint32.
+
+// MarshalBinary encodes fp for transmission to a third-party verifier.
+func (fp *Proof) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+
+	writeBytes := func(b []byte) {
+		var lenBuf [4]byte
+		binary.LittleEndian.PutUint32(lenBuf[:], uint32(len(b)))
+		buf.Write(lenBuf[:])
+		buf.Write(b)
+	}
+	writeUint64 := func(v uint64) {
+		var b [8]byte
+		binary.LittleEndian.PutUint64(b[:], v)
+		buf.Write(b[:])
+	}
+
+	buf.WriteByte(byte(fp.Reason))
+	writeBytes(fp.Commitment)
+	writeBytes(fp.Challenge)
+	writeUint64(fp.Index)
+	writeBytes(fp.ClaimedLabel)
+	writeBytes(fp.ExpectedLabel)
+	writeBytes(fp.Root)
+
+	var k1Buf [4]byte
+	binary.LittleEndian.PutUint32(k1Buf[:], fp.K1)
+	buf.Write(k1Buf[:])
+	writeUint64(fp.NumLabels)
+
+	var pathLenBuf [4]byte
+	binary.LittleEndian.PutUint32(pathLenBuf[:], uint32(len(fp.MerklePath)))
+	buf.Write(pathLenBuf[:])
+	for i, sibling := range fp.MerklePath {
+		writeBytes(sibling)
+		writeUint64(uint64(fp.MerklePathIndices[i]))
+	}
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary decodes a Proof produced by MarshalBinary.
+func (fp *Proof) UnmarshalBinary(data []byte) error {
+	r := bytes.NewReader(data)
+
+	reason, err := r.ReadByte()
+	if err != nil {
+		return fmt.Errorf("fraud: reading reason: %w", err)
+	}
+	fp.Reason = Reason(reason)
+
+	readBytes := func() ([]byte, error) {
+		var lenBuf [4]byte
+		if _, err := io.ReadFull(r, lenBuf[:]); err != nil {
+			return nil, err
+		}
+		n := binary.LittleEndian.Uint32(lenBuf[:])
+		b := make([]byte, n)
+		if _, err := io.ReadFull(r, b); err != nil {
+			return nil, err
+		}
+		return b, nil
+	}
+	readUint64 := func() (uint64, error) {
+		var b [8]byte
+		if _, err := io.ReadFull(r, b[:]); err != nil {
+			return 0, err
+		}
+		return binary.LittleEndian.Uint64(b[:]), nil
+	}
+
+	if fp.Commitment, err = readBytes(); err != nil {
+		return fmt.Errorf("fraud: reading commitment: %w", err)
+	}
+	if fp.Challenge, err = readBytes(); err != nil {
+		return fmt.Errorf("fraud: reading challenge: %w", err)
+	}
+	if fp.Index, err = readUint64(); err != nil {
+		return fmt.Errorf("fraud: reading index: %w", err)
+	}
+
+	claimed, err := readBytes()
+	if err != nil {
+		return fmt.Errorf("fraud: reading claimed label: %w", err)
+	}
+	fp.ClaimedLabel = datatypes.Label(claimed)
+
+	expected, err := readBytes()
+	if err != nil {
+		return fmt.Errorf("fraud: reading expected label: %w", err)
+	}
+	fp.ExpectedLabel = datatypes.Label(expected)
+
+	if fp.Root, err = readBytes(); err != nil {
+		return fmt.Errorf("fraud: reading root: %w", err)
+	}
+
+	var k1Buf [4]byte
+	if _, err := io.ReadFull(r, k1Buf[:]); err != nil {
+		return fmt.Errorf("fraud: reading k1: %w", err)
+	}
+	fp.K1 = binary.LittleEndian.Uint32(k1Buf[:])
+	if fp.NumLabels, err = readUint64(); err != nil {
+		return fmt.Errorf("fraud: reading num labels: %w", err)
+	}
+
+	var pathLenBuf [4]byte
+	if _, err := io.ReadFull(r, pathLenBuf[:]); err != nil {
+		return fmt.Errorf("fraud: reading merkle path length: %w", err)
+	}
+	pathLen := binary.LittleEndian.Uint32(pathLenBuf[:])
+	fp.MerklePath = make([][]byte, pathLen)
+	fp.MerklePathIndices = make([]int64, pathLen)
+	for i := range fp.MerklePath {
+		if fp.MerklePath[i], err = readBytes(); err != nil {
+			return fmt.Errorf("fraud: reading merkle path sibling %d: %w", i, err)
+		}
+		idx, err := readUint64()
+		if err != nil {
+			return fmt.Errorf("fraud: reading merkle path index %d: %w", i, err)
+		}
+		fp.MerklePathIndices[i] = int64(idx)
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package fraud
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/post/datatypes"
+	"github.com/spacemeshos/post/shared"
+)
+
+// k1 and numLabels are fixed difficulty parameters used to find labels that
+// provably do, or don't, pass the K1/NumLabels requirement below.
+const (
+	k1        = uint32(1)
+	numLabels = uint64(2)
+)
+
+func findLabels(t *testing.T) (failing, passing datatypes.Label) {
+	t.Helper()
+	challenge := make([]byte, 32)
+	difficulty := shared.Difficulty(k1, numLabels)
+	for i := uint64(0); ; i++ {
+		l := datatypes.NewLabel(i)
+		if shared.IndexHash(l, challenge) > difficulty {
+			if failing == nil {
+				failing = l
+			}
+		} else if passing == nil {
+			passing = l
+		}
+		if failing != nil && passing != nil {
+			return failing, passing
+		}
+	}
+}
+
+func buildProof(t *testing.T, reason Reason, claimed datatypes.Label) *Proof {
+	t.Helper()
+
+	sibling0 := datatypes.NewLabel(8)
+	sibling1 := datatypes.NewLabel(9)
+	path := [][]byte{sibling0, sibling1}
+	pathIndex := []int64{0, 1}
+
+	root, err := recomputeRoot(claimed, path, pathIndex)
+	require.NoError(t, err)
+
+	fp := &Proof{
+		Commitment:        make([]byte, 32),
+		Challenge:         make([]byte, 32),
+		Reason:            reason,
+		Index:             2,
+		ClaimedLabel:      claimed,
+		ExpectedLabel:     datatypes.NewLabel(42),
+		MerklePath:        path,
+		MerklePathIndices: pathIndex,
+		Root:              root,
+		K1:                k1,
+		NumLabels:         numLabels,
+	}
+	return fp
+}
+
+func TestVerify_BadLabel(t *testing.T) {
+	r := require.New(t)
+
+	fp := buildProof(t, BadLabel, datatypes.NewLabel(7))
+	ok, err := Verify(fp)
+	r.NoError(err)
+	r.True(ok)
+}
+
+func TestVerify_BadIndex(t *testing.T) {
+	r := require.New(t)
+
+	failing, _ := findLabels(t)
+	fp := buildProof(t, BadIndex, failing)
+	ok, err := Verify(fp)
+	r.NoError(err)
+	r.True(ok)
+}
+
+func TestVerify_BadIndex_RejectsPassingLabel(t *testing.T) {
+	r := require.New(t)
+
+	_, passing := findLabels(t)
+	fp := buildProof(t, BadIndex, passing)
+	ok, err := Verify(fp)
+	r.False(ok)
+	r.ErrorIs(err, ErrIndexPasses)
+}
+
+func TestVerify_RootMismatch(t *testing.T) {
+	r := require.New(t)
+
+	fp := buildProof(t, BadLabel, datatypes.NewLabel(7))
+	fp.Root = datatypes.NewLabel(0)
+
+	ok, err := Verify(fp)
+	r.False(ok)
+	r.ErrorIs(err, ErrRootMismatch)
+}
+
+func TestVerify_LabelsMatch(t *testing.T) {
+	r := require.New(t)
+
+	fp := buildProof(t, BadLabel, datatypes.NewLabel(7))
+	fp.ExpectedLabel = fp.ClaimedLabel
+
+	ok, err := Verify(fp)
+	r.False(ok)
+	r.ErrorIs(err, ErrLabelsMatch)
+}
+
+func TestProof_MarshalUnmarshalBinary(t *testing.T) {
+	r := require.New(t)
+
+	fp := buildProof(t, BadLabel, datatypes.NewLabel(7))
+
+	data, err := fp.MarshalBinary()
+	r.NoError(err)
+
+	var got Proof
+	r.NoError(got.UnmarshalBinary(data))
+
+	r.Equal(fp.Commitment, got.Commitment)
+	r.Equal(fp.Challenge, got.Challenge)
+	r.Equal(fp.Reason, got.Reason)
+	r.Equal(fp.Index, got.Index)
+	r.Equal(fp.ClaimedLabel, got.ClaimedLabel)
+	r.Equal(fp.ExpectedLabel, got.ExpectedLabel)
+	r.Equal(fp.Root, got.Root)
+	r.Equal(fp.K1, got.K1)
+	r.Equal(fp.NumLabels, got.NumLabels)
+	r.Equal(fp.MerklePath, got.MerklePath)
+	r.Equal(fp.MerklePathIndices, got.MerklePathIndices)
+
+	ok, err := Verify(&got)
+	r.NoError(err)
+	r.True(ok)
+}
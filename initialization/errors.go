@@ -0,0 +1,17 @@
+package initialization
+
+import "fmt"
+
+// ConfigMismatchError is returned by a read-only Initializer's Initialize,
+// and by proving.Prover.GenerateProof, when the config or commitment it was
+// constructed with doesn't match what's recorded in a data directory's
+// metadata.
+type ConfigMismatchError struct {
+	Param    string
+	Expected interface{}
+	Given    interface{}
+}
+
+func (e ConfigMismatchError) Error() string {
+	return fmt.Sprintf("config mismatch on %s: expected %v, given %v", e.Param, e.Expected, e.Given)
+}
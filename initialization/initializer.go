@@ -0,0 +1,158 @@
+package initialization
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spacemeshos/post/shared"
+)
+
+// Initializer writes (or verifies) the label data for a PoST data
+// directory.
+type Initializer struct {
+	options
+}
+
+// NewInitializer constructs an Initializer from the given options. A
+// commitment, config and InitOpts are required.
+func NewInitializer(opts ...OptionFunc) (*Initializer, error) {
+	o := &options{}
+	for _, opt := range opts {
+		if err := opt(o); err != nil {
+			return nil, err
+		}
+	}
+	if o.logger == nil {
+		o.logger = shared.DisabledLogger{}
+	}
+	if err := o.validate(); err != nil {
+		return nil, err
+	}
+	return &Initializer{options: *o}, nil
+}
+
+func LabelFileName(dataDir string, fileIndex uint) string {
+	return filepath.Join(dataDir, fmt.Sprintf("postdata_%d.bin", fileIndex))
+}
+
+// fileLayout splits numLabels labels across numFiles files as evenly as
+// possible, returning each file's label count.
+func fileLayout(numLabels uint64, numFiles uint) []uint64 {
+	counts := make([]uint64, numFiles)
+	base := numLabels / uint64(numFiles)
+	rem := numLabels % uint64(numFiles)
+	for i := range counts {
+		counts[i] = base
+		if uint64(i) < rem {
+			counts[i]++
+		}
+	}
+	return counts
+}
+
+// Initialize writes every label file and the metadata file for this
+// Initializer's data directory, computing labels on demand from the
+// commitment. It's idempotent: calling it again over an already-complete
+// directory recomputes and rewrites the same content.
+//
+// With WithReadOnly(true), Initialize doesn't write anything: it instead
+// checks that the directory already holds a complete, valid dataset for
+// this commitment and config, and fails fast otherwise. See
+// validateReadOnly.
+func (init *Initializer) Initialize(ctx context.Context) error {
+	if init.readOnly {
+		return init.validateReadOnly()
+	}
+
+	if err := os.MkdirAll(init.initOpts.DataDir, 0o755); err != nil {
+		return fmt.Errorf("creating data directory: %w", err)
+	}
+
+	unlock, err := AcquireLock(init.initOpts.DataDir, false, true)
+	if err != nil {
+		return fmt.Errorf("initialization: %w", err)
+	}
+	defer unlock()
+
+	numLabels := init.cfg.LabelsPerUnit * uint64(init.initOpts.NumUnits)
+	labelSize := LabelSize(init.cfg.BitsPerLabel)
+	counts := fileLayout(numLabels, init.initOpts.NumFiles)
+
+	var index uint64
+	for fileIdx, count := range counts {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		buf := make([]byte, 0, count*uint64(labelSize))
+		for i := uint64(0); i < count; i++ {
+			buf = append(buf, ComputeLabel(init.commitment, index, init.cfg.BitsPerLabel)...)
+			index++
+		}
+		if err := os.WriteFile(LabelFileName(init.initOpts.DataDir, uint(fileIdx)), buf, 0o644); err != nil {
+			return fmt.Errorf("writing label file %d: %w", fileIdx, err)
+		}
+
+		init.logger.Info("initialized file %d/%d (%d labels)", fileIdx+1, len(counts), count)
+	}
+
+	return writeMetadata(init.initOpts.DataDir, &Metadata{
+		Commitment:    init.commitment,
+		BitsPerLabel:  init.cfg.BitsPerLabel,
+		LabelsPerUnit: init.cfg.LabelsPerUnit,
+		NumUnits:      init.initOpts.NumUnits,
+		NumFiles:      init.initOpts.NumFiles,
+	})
+}
+
+// validateReadOnly checks, without writing anything, that init's data
+// directory already holds a complete dataset matching init's commitment,
+// cfg and initOpts: its metadata agrees on every parameter, and every
+// label file is present at its expected size. It takes a shared advisory
+// lock for the duration of the check, so it can run alongside any number
+// of other read-only opens but not a concurrent writer.
+func (init *Initializer) validateReadOnly() error {
+	unlock, err := AcquireLock(init.initOpts.DataDir, true, false)
+	if err != nil {
+		return fmt.Errorf("initialization: read-only open requires a previously initialized data directory: %w", err)
+	}
+	defer unlock()
+
+	meta, err := ReadMetadata(init.initOpts.DataDir)
+	if err != nil {
+		return fmt.Errorf("initialization: read-only open requires a previously initialized data directory: %w", err)
+	}
+	if !bytes.Equal(meta.Commitment, init.commitment) {
+		return ConfigMismatchError{Param: "Commitment", Expected: meta.Commitment, Given: init.commitment}
+	}
+	if meta.BitsPerLabel != init.cfg.BitsPerLabel {
+		return ConfigMismatchError{Param: "BitsPerLabel", Expected: meta.BitsPerLabel, Given: init.cfg.BitsPerLabel}
+	}
+	if meta.LabelsPerUnit != init.cfg.LabelsPerUnit {
+		return ConfigMismatchError{Param: "LabelsPerUnit", Expected: meta.LabelsPerUnit, Given: init.cfg.LabelsPerUnit}
+	}
+	if meta.NumUnits != init.initOpts.NumUnits {
+		return ConfigMismatchError{Param: "NumUnits", Expected: meta.NumUnits, Given: init.initOpts.NumUnits}
+	}
+	if meta.NumFiles != init.initOpts.NumFiles {
+		return ConfigMismatchError{Param: "NumFiles", Expected: meta.NumFiles, Given: init.initOpts.NumFiles}
+	}
+
+	numLabels := init.cfg.LabelsPerUnit * uint64(init.initOpts.NumUnits)
+	labelSize := uint64(LabelSize(init.cfg.BitsPerLabel))
+	for fileIdx, count := range fileLayout(numLabels, init.initOpts.NumFiles) {
+		info, err := os.Stat(LabelFileName(init.initOpts.DataDir, uint(fileIdx)))
+		if err != nil {
+			return fmt.Errorf("initialization: read-only open: data incomplete: %w", err)
+		}
+		if want := count * labelSize; uint64(info.Size()) != want {
+			return fmt.Errorf("initialization: read-only open: data incomplete: label file %d is %d bytes, want %d", fileIdx, info.Size(), want)
+		}
+	}
+	return nil
+}
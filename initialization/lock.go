@@ -0,0 +1,9 @@
+package initialization
+
+import "path/filepath"
+
+const lockFileName = "postdata_lock"
+
+func lockPath(dataDir string) string {
+	return filepath.Join(dataDir, lockFileName)
+}
@@ -0,0 +1,31 @@
+//go:build !unix
+
+package initialization
+
+import (
+	"fmt"
+	"os"
+)
+
+// AcquireLock is the non-unix fallback: advisory file locking isn't
+// implemented on this platform, so callers proceed without mutual
+// exclusion. It still creates the lock file when create is true (so a
+// later read-only open on this platform, or on a unix host sharing the
+// data directory, finds it) and still fails fast when create is false and
+// the lock file doesn't exist - the signal a read-only Initialize relies
+// on to detect an uninitialized data directory.
+func AcquireLock(dataDir string, shared, create bool) (func() error, error) {
+	if !create {
+		if _, err := os.Stat(lockPath(dataDir)); err != nil {
+			return nil, fmt.Errorf("opening lock file: %w", err)
+		}
+		return func() error { return nil }, nil
+	}
+
+	f, err := os.OpenFile(lockPath(dataDir), os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("opening lock file: %w", err)
+	}
+	f.Close()
+	return func() error { return nil }, nil
+}
@@ -0,0 +1,48 @@
+//go:build unix
+
+package initialization
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// AcquireLock takes an advisory lock on dataDir's lock file and returns a
+// function that releases it. An exclusive lock (shared false) excludes any
+// other holder, shared or exclusive - used by a writing Initialize. A
+// shared lock (shared true) excludes only a concurrent exclusive holder,
+// tolerating any number of other shared holders - used by read-only
+// Initialize and Prover opens.
+//
+// create controls whether the lock file may be created if it doesn't
+// already exist. Read-only callers pass false: a missing lock file means
+// the data directory has never been initialized, and read-only mode must
+// fail fast on that rather than create one itself.
+//
+// It's exported so proving can take the same shared lock proving opens the
+// data directory under, without duplicating the locking logic.
+func AcquireLock(dataDir string, shared, create bool) (func() error, error) {
+	flags := os.O_RDONLY
+	if create {
+		flags = os.O_RDWR | os.O_CREATE
+	}
+	f, err := os.OpenFile(lockPath(dataDir), flags, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("opening lock file: %w", err)
+	}
+
+	how := syscall.LOCK_EX
+	if shared {
+		how = syscall.LOCK_SH
+	}
+	if err := syscall.Flock(int(f.Fd()), how); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("acquiring lock: %w", err)
+	}
+
+	return func() error {
+		defer f.Close()
+		return syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+	}, nil
+}
@@ -0,0 +1,76 @@
+package initialization
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spacemeshos/post/datatypes"
+)
+
+const metadataFileName = "postdata_metadata.json"
+
+// Metadata records the commitment, config parameters and file layout a data
+// directory was initialized with, so a later reader can check it's opened
+// under the same ones.
+type Metadata struct {
+	Commitment    []byte
+	BitsPerLabel  uint
+	LabelsPerUnit uint64
+	NumUnits      uint
+	NumFiles      uint
+}
+
+func metadataPath(dataDir string) string {
+	return filepath.Join(dataDir, metadataFileName)
+}
+
+func writeMetadata(dataDir string, m *Metadata) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("marshaling metadata: %w", err)
+	}
+	if err := os.WriteFile(metadataPath(dataDir), data, 0o644); err != nil {
+		return fmt.Errorf("writing metadata: %w", err)
+	}
+	return nil
+}
+
+// ReadMetadata reads and parses the metadata file from dataDir. It's
+// exported so packages other than initialization (namely proving) can
+// validate a data directory without duplicating the file format.
+func ReadMetadata(dataDir string) (*Metadata, error) {
+	data, err := os.ReadFile(metadataPath(dataDir))
+	if err != nil {
+		return nil, fmt.Errorf("reading metadata: %w", err)
+	}
+	var m Metadata
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parsing metadata: %w", err)
+	}
+	return &m, nil
+}
+
+// LabelSize returns the number of bytes a single label occupies on disk for
+// the given bit size.
+func LabelSize(bitsPerLabel uint) uint {
+	return (bitsPerLabel + 7) / 8
+}
+
+// ComputeLabel deterministically derives the label at index under
+// commitment. It's the only place label content is defined, so the writer
+// (Initialize) and anyone independently checking a prover's claims (e.g.
+// fraud proof construction) always agree on what a given index should
+// contain.
+func ComputeLabel(commitment []byte, index uint64, bitsPerLabel uint) datatypes.Label {
+	h := sha256.New()
+	h.Write(commitment)
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], index)
+	h.Write(b[:])
+	sum := h.Sum(nil)
+	return datatypes.Label(sum[:LabelSize(bitsPerLabel)])
+}
@@ -0,0 +1,88 @@
+package initialization
+
+import (
+	"errors"
+
+	"github.com/spacemeshos/post/config"
+	"github.com/spacemeshos/post/shared"
+)
+
+// options collects everything NewInitializer's functional options set.
+type options struct {
+	commitment []byte
+	cfg        config.Config
+	initOpts   config.InitOpts
+	logger     shared.Logger
+	readOnly   bool
+}
+
+// OptionFunc configures a new Initializer.
+type OptionFunc func(*options) error
+
+// WithCommitment sets the commitment (the PoST identity) data is
+// initialized under.
+func WithCommitment(commitment []byte) OptionFunc {
+	return func(o *options) error {
+		o.commitment = commitment
+		return nil
+	}
+}
+
+// WithConfig sets the PoST instance parameters.
+func WithConfig(cfg config.Config) OptionFunc {
+	return func(o *options) error {
+		o.cfg = cfg
+		return nil
+	}
+}
+
+// WithInitOpts sets the data directory, unit count, and file layout for
+// this initialization.
+func WithInitOpts(initOpts config.InitOpts) OptionFunc {
+	return func(o *options) error {
+		o.initOpts = initOpts
+		return nil
+	}
+}
+
+// WithLogger sets the logger used to report initialization progress.
+func WithLogger(logger shared.Logger) OptionFunc {
+	return func(o *options) error {
+		o.logger = logger
+		return nil
+	}
+}
+
+// WithReadOnly opens the data directory without modifying it: no metadata
+// rewrite and no attempt to write or resume missing label data. Initialize
+// instead checks the directory under a shared advisory lock - tolerating
+// any number of other read-only holders - and fails fast if the metadata
+// doesn't match commitment/cfg/initOpts, or any label file is missing or
+// the wrong size, rather than attempting to complete it.
+//
+// This is what lets several prover processes on the same host open one
+// completed PoST data directory concurrently - e.g. to generate proofs for
+// different challenges in parallel - without the writer contention the
+// default read-write mode's metadata rewrite would otherwise cause.
+func WithReadOnly(readOnly bool) OptionFunc {
+	return func(o *options) error {
+		o.readOnly = readOnly
+		return nil
+	}
+}
+
+func (o *options) validate() error {
+	if len(o.commitment) == 0 {
+		return errors.New("initialization: commitment is required")
+	}
+	if o.initOpts.DataDir == "" {
+		return errors.New("initialization: data directory is required")
+	}
+	if o.initOpts.NumUnits < o.cfg.MinNumUnits {
+		return errors.New("initialization: num units below config minimum")
+	}
+	if o.initOpts.NumFiles == 0 {
+		return errors.New("initialization: num files must be at least 1")
+	}
+	return nil
+}
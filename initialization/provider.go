@@ -0,0 +1,7 @@
+package initialization
+
+// CPUProviderID returns the compute provider id for plain-CPU initialization,
+// the only provider this package implements today.
+func CPUProviderID() uint32 {
+	return 0
+}
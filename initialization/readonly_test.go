@@ -0,0 +1,71 @@
+package initialization
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/post/config"
+)
+
+func TestInitializer_ReadOnly_FailsFastOnIncompleteData(t *testing.T) {
+	r := require.New(t)
+
+	cfg := config.DefaultConfig()
+	cfg.LabelsPerUnit = 1 << 12
+
+	opts := config.DefaultInitOpts()
+	opts.DataDir = t.TempDir()
+	opts.NumUnits = cfg.MinNumUnits
+	opts.NumFiles = 1
+	opts.ComputeProviderID = CPUProviderID()
+
+	commitment := make([]byte, 32)
+
+	init, err := NewInitializer(
+		WithCommitment(commitment),
+		WithConfig(cfg),
+		WithInitOpts(opts),
+		WithReadOnly(true),
+	)
+	r.NoError(err)
+
+	// An empty data directory can never satisfy a read-only open: there's
+	// nothing to resume from, and read-only mode must not attempt to write
+	// the missing data itself.
+	err = init.Initialize(context.Background())
+	r.Error(err)
+}
+
+func TestInitializer_ReadOnly_OpensCompletedData(t *testing.T) {
+	r := require.New(t)
+
+	cfg := config.DefaultConfig()
+	cfg.LabelsPerUnit = 1 << 12
+
+	opts := config.DefaultInitOpts()
+	opts.DataDir = t.TempDir()
+	opts.NumUnits = cfg.MinNumUnits
+	opts.NumFiles = 1
+	opts.ComputeProviderID = CPUProviderID()
+
+	commitment := make([]byte, 32)
+
+	writer, err := NewInitializer(
+		WithCommitment(commitment),
+		WithConfig(cfg),
+		WithInitOpts(opts),
+	)
+	r.NoError(err)
+	r.NoError(writer.Initialize(context.Background()))
+
+	reader, err := NewInitializer(
+		WithCommitment(commitment),
+		WithConfig(cfg),
+		WithInitOpts(opts),
+		WithReadOnly(true),
+	)
+	r.NoError(err)
+	r.NoError(reader.Initialize(context.Background()))
+}
@@ -0,0 +1,22 @@
+package proving
+
+// OptionFunc configures a new Prover.
+type OptionFunc func(*Prover) error
+
+// ReadOnly configures a Prover to open its data directory under a shared
+// advisory lock that tolerates any number of other read-only holders,
+// rather than the exclusive lock a writing Initialize takes. Prover never
+// writes to its data directory in the first place - GenerateProof only
+// reads label files and never rewrites metadata - so this mostly documents
+// and checks that fact: any number of read-only Provers can open the same
+// completed dataset concurrently, e.g. to generate proofs for different
+// challenges in parallel, without contending with one another. A writer is
+// still excluded: a read-only open waits for a writing Initialize in
+// progress, and a writing Initialize waits until every read-only Prover on
+// the directory has been closed.
+func ReadOnly() OptionFunc {
+	return func(p *Prover) error {
+		p.readOnly = true
+		return nil
+	}
+}
@@ -0,0 +1,213 @@
+// Package proving generates PoST proofs: compact demonstrations that a
+// prover holds K2 labels, selected from an initialized data directory by a
+// challenge, that satisfy the K1/NumLabels difficulty requirement.
+package proving
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/cbergoon/merkletree"
+
+	"github.com/spacemeshos/post/config"
+	"github.com/spacemeshos/post/datatypes"
+	"github.com/spacemeshos/post/initialization"
+	"github.com/spacemeshos/post/shared"
+	"github.com/spacemeshos/post/vdf"
+)
+
+// Challenge selects which label indices a proof must cover.
+type Challenge = shared.Challenge
+
+// Prover generates proofs against a single initialized data directory.
+type Prover struct {
+	cfg        config.Config
+	dataDir    string
+	commitment []byte
+	meta       *initialization.Metadata
+	logger     shared.Logger
+	readOnly   bool
+	unlock     func() error
+}
+
+// NewProver opens dataDir for proving under cfg and commitment. The
+// commitment and config aren't checked against the data directory's
+// metadata until GenerateProof, so opening a Prover never fails just
+// because a caller is about to probe it with the wrong parameters.
+//
+// With the ReadOnly option, NewProver additionally takes a shared advisory
+// lock on the data directory, held for the Prover's lifetime (release it
+// with Close), so several read-only Provers can open it concurrently
+// without contending with each other, while a writing Initialize, which
+// needs the exclusive lock, waits until all of them have been closed.
+func NewProver(cfg config.Config, dataDir string, commitment []byte, opts ...OptionFunc) (*Prover, error) {
+	p := &Prover{
+		cfg:        cfg,
+		dataDir:    dataDir,
+		commitment: commitment,
+		logger:     shared.DisabledLogger{},
+	}
+	for _, opt := range opts {
+		if err := opt(p); err != nil {
+			return nil, err
+		}
+	}
+
+	// Acquire the shared lock, if any, before reading metadata - not after -
+	// so a read-only open is actually protected against a concurrent writer
+	// rewriting it mid-read, matching initialization.Initializer.validateReadOnly.
+	if p.readOnly {
+		unlock, err := initialization.AcquireLock(dataDir, true, false)
+		if err != nil {
+			return nil, fmt.Errorf("proving: read-only open: %w", err)
+		}
+		p.unlock = unlock
+	}
+
+	meta, err := initialization.ReadMetadata(dataDir)
+	if err != nil {
+		if p.unlock != nil {
+			p.unlock()
+		}
+		return nil, fmt.Errorf("reading data directory metadata: %w", err)
+	}
+	p.meta = meta
+
+	return p, nil
+}
+
+// Close releases the shared advisory lock taken by the ReadOnly option. It's
+// a no-op for a Prover opened without ReadOnly.
+func (p *Prover) Close() error {
+	if p.unlock == nil {
+		return nil
+	}
+	return p.unlock()
+}
+
+// SetLogger replaces the Prover's logger.
+func (p *Prover) SetLogger(logger shared.Logger) {
+	p.logger = logger
+}
+
+// readLabels loads every label in the data directory, in index order.
+func (p *Prover) readLabels() ([]datatypes.Label, error) {
+	numLabels := p.meta.LabelsPerUnit * uint64(p.meta.NumUnits)
+	labelSize := initialization.LabelSize(p.meta.BitsPerLabel)
+
+	labels := make([]datatypes.Label, 0, numLabels)
+	for fileIdx := uint(0); fileIdx < p.meta.NumFiles; fileIdx++ {
+		data, err := os.ReadFile(initialization.LabelFileName(p.dataDir, fileIdx))
+		if err != nil {
+			return nil, fmt.Errorf("reading label file %d: %w", fileIdx, err)
+		}
+		for off := 0; off < len(data); off += int(labelSize) {
+			labels = append(labels, datatypes.Label(data[off:off+int(labelSize)]))
+		}
+	}
+	return labels, nil
+}
+
+// GenerateProof scans the data directory for the first K2 labels that pass
+// the K1/NumLabels difficulty requirement under ch (under VDF(ch) when
+// p.cfg.VDFEnabled), and returns a proof of
+// their inclusion. It's equivalent to
+// GenerateProofWithContext(context.Background(), ch).
+func (p *Prover) GenerateProof(ch Challenge) (*shared.Proof, *shared.ProofMetaData, error) {
+	return p.GenerateProofWithContext(context.Background(), ch)
+}
+
+// GenerateProofWithContext is GenerateProof, cancellable via ctx. Cancelling
+// ctx only has a practical effect while p.cfg.VDFEnabled, since that's the
+// only potentially long-running stage of proof generation; mirrors
+// initialization.Initializer.Initialize, which accepts a context for the
+// same reason.
+func (p *Prover) GenerateProofWithContext(ctx context.Context, ch Challenge) (*shared.Proof, *shared.ProofMetaData, error) {
+	if !bytes.Equal(p.meta.Commitment, p.commitment) {
+		return nil, nil, initialization.ConfigMismatchError{Param: "Commitment", Expected: p.meta.Commitment, Given: p.commitment}
+	}
+	if p.meta.BitsPerLabel != p.cfg.BitsPerLabel {
+		return nil, nil, initialization.ConfigMismatchError{Param: "BitsPerLabel", Expected: p.meta.BitsPerLabel, Given: p.cfg.BitsPerLabel}
+	}
+	if p.meta.LabelsPerUnit != p.cfg.LabelsPerUnit {
+		return nil, nil, initialization.ConfigMismatchError{Param: "LabelsPerUnit", Expected: p.meta.LabelsPerUnit, Given: p.cfg.LabelsPerUnit}
+	}
+
+	selectionChallenge := []byte(ch)
+	var vdfProof *vdf.Proof
+	var vdfIterations uint64
+	var vdfDiscriminantBits int
+	if p.cfg.VDFEnabled {
+		vdfIterations = p.cfg.VDFIterations
+		vdfDiscriminantBits = p.cfg.VDFDiscriminantBits
+		vdfCfg := vdf.Config{Iterations: vdfIterations, DiscriminantBits: vdfDiscriminantBits}
+		gated, proof, err := vdf.Generate(ctx, ch, vdfCfg)
+		if err != nil {
+			return nil, nil, fmt.Errorf("proving: VDF gating: %w", err)
+		}
+		selectionChallenge = gated
+		vdfProof = proof
+	}
+
+	labels, err := p.readLabels()
+	if err != nil {
+		return nil, nil, err
+	}
+	numLabels := uint64(len(labels))
+
+	contents := make([]merkletree.Content, len(labels))
+	for i, l := range labels {
+		contents[i] = l
+	}
+	tree, err := merkletree.NewTree(contents)
+	if err != nil {
+		return nil, nil, fmt.Errorf("building label tree: %w", err)
+	}
+
+	var qualifying []uint64
+	for idx := uint64(0); idx < numLabels && uint32(len(qualifying)) < p.cfg.K2; idx++ {
+		if shared.PassesDifficulty(labels[idx], selectionChallenge, p.cfg.K1, numLabels) {
+			qualifying = append(qualifying, idx)
+		}
+	}
+	if uint32(len(qualifying)) < p.cfg.K2 {
+		return nil, nil, fmt.Errorf("proving: only found %d of %d required qualifying labels", len(qualifying), p.cfg.K2)
+	}
+
+	proof := &shared.Proof{
+		Labels:            make([][]byte, len(qualifying)),
+		MerklePaths:       make([][][]byte, len(qualifying)),
+		MerklePathIndices: make([][]int64, len(qualifying)),
+	}
+	for i, idx := range qualifying {
+		path, pathIndex, err := tree.GetMerklePath(labels[idx])
+		if err != nil {
+			return nil, nil, fmt.Errorf("building merkle path for index %d: %w", idx, err)
+		}
+		proof.Labels[i] = labels[idx]
+		proof.MerklePaths[i] = path
+		proof.MerklePathIndices[i] = pathIndex
+	}
+
+	indexBitSize := shared.BinaryRepresentationMinBits(numLabels)
+	proof.Indices = shared.PackIndices(qualifying, indexBitSize)
+
+	metadata := &shared.ProofMetaData{
+		Commitment:          p.commitment,
+		Challenge:           ch,
+		BitsPerLabel:        p.cfg.BitsPerLabel,
+		LabelsPerUnit:       p.cfg.LabelsPerUnit,
+		NumUnits:            p.meta.NumUnits,
+		K1:                  p.cfg.K1,
+		K2:                  p.cfg.K2,
+		MerkleRoot:          tree.MerkleRoot(),
+		VDFProof:            vdfProof,
+		VDFIterations:       vdfIterations,
+		VDFDiscriminantBits: vdfDiscriminantBits,
+	}
+
+	p.logger.Info("generated proof: %d qualifying labels out of %d", len(qualifying), numLabels)
+	return proof, metadata, nil
+}
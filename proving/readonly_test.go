@@ -0,0 +1,42 @@
+package proving
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/post/initialization"
+)
+
+func TestProver_ReadOnly_MultipleConcurrentOpens(t *testing.T) {
+	r := require.New(t)
+
+	cfg, opts := getTestConfig(t)
+
+	init, err := NewInitializer(
+		initialization.WithCommitment(commitment),
+		initialization.WithConfig(cfg),
+		initialization.WithInitOpts(opts),
+	)
+	r.NoError(err)
+	r.NoError(init.Initialize(context.Background()))
+
+	// Two independent read-only Provers over the same completed dataset
+	// must not contend with each other, since neither rewrites metadata or
+	// truncates files.
+	p1, err := NewProver(cfg, opts.DataDir, commitment, ReadOnly())
+	r.NoError(err)
+	p2, err := NewProver(cfg, opts.DataDir, commitment, ReadOnly())
+	r.NoError(err)
+
+	ch1 := make(Challenge, 32)
+	ch1[0] = 1
+	ch2 := make(Challenge, 32)
+	ch2[0] = 2
+
+	_, _, err = p1.GenerateProof(ch1)
+	r.NoError(err)
+	_, _, err = p2.GenerateProof(ch2)
+	r.NoError(err)
+}
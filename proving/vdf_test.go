@@ -0,0 +1,76 @@
+package proving
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/post/initialization"
+	"github.com/spacemeshos/post/verifying"
+)
+
+func TestProver_GenerateProof_VDFGated(t *testing.T) {
+	r := require.New(t)
+
+	cfg, opts := getTestConfig(t)
+	cfg.VDFEnabled = true
+	cfg.VDFIterations = 50
+	cfg.VDFDiscriminantBits = 256
+
+	init, err := NewInitializer(
+		initialization.WithCommitment(commitment),
+		initialization.WithConfig(cfg),
+		initialization.WithInitOpts(opts),
+	)
+	r.NoError(err)
+	r.NoError(init.Initialize(context.Background()))
+
+	p, err := NewProver(cfg, opts.DataDir, commitment)
+	r.NoError(err)
+
+	proof, proofMetaData, err := p.GenerateProofWithContext(context.Background(), ch)
+	r.NoError(err)
+	r.NotNil(proofMetaData.VDFProof)
+	r.Equal(cfg.VDFIterations, proofMetaData.VDFIterations)
+	r.Equal(cfg.VDFDiscriminantBits, proofMetaData.VDFDiscriminantBits)
+
+	r.NoError(verifying.Verify(proof, proofMetaData))
+
+	// Tampering with the embedded VDF proof must be caught by the VDF gate
+	// check, before any Merkle-path or index-hash work runs.
+	tampered := *proofMetaData
+	tamperedVDFProof := *proofMetaData.VDFProof
+	tamperedVDFProof.Y = append([]byte{}, tamperedVDFProof.Y...)
+	tamperedVDFProof.Y[0] ^= 0xff
+	tampered.VDFProof = &tamperedVDFProof
+
+	err = verifying.Verify(proof, &tampered)
+	r.Error(err)
+}
+
+func TestProver_GenerateProof_ContextCancelled(t *testing.T) {
+	r := require.New(t)
+
+	cfg, opts := getTestConfig(t)
+	cfg.VDFEnabled = true
+	cfg.VDFIterations = 1 << 20
+	cfg.VDFDiscriminantBits = 256
+
+	init, err := NewInitializer(
+		initialization.WithCommitment(commitment),
+		initialization.WithConfig(cfg),
+		initialization.WithInitOpts(opts),
+	)
+	r.NoError(err)
+	r.NoError(init.Initialize(context.Background()))
+
+	p, err := NewProver(cfg, opts.DataDir, commitment)
+	r.NoError(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, _, err = p.GenerateProofWithContext(ctx, ch)
+	r.Error(err)
+}
@@ -0,0 +1,27 @@
+package shared
+
+import "math/bits"
+
+// BinaryRepresentationMinBits returns the number of bits needed to represent
+// any index in [0, numLabels) as a fixed-width integer.
+func BinaryRepresentationMinBits(numLabels uint64) uint {
+	if numLabels <= 1 {
+		return 0
+	}
+	return uint(bits.Len64(numLabels - 1))
+}
+
+// Size returns the number of bytes needed to pack k fixed-width values of
+// indexBitSize bits each into a tightly bit-packed buffer.
+func Size(indexBitSize uint, k uint) uint {
+	totalBits := indexBitSize * k
+	return (totalBits + 7) / 8
+}
+
+// Uint64MulOverflow reports whether a*b overflows uint64.
+func Uint64MulOverflow(a, b uint64) bool {
+	if a == 0 || b == 0 {
+		return false
+	}
+	return a > (^uint64(0))/b
+}
@@ -0,0 +1,5 @@
+package shared
+
+// Challenge is the per-proof input that selects which label indices a
+// Prover must demonstrate knowledge of.
+type Challenge []byte
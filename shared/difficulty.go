@@ -0,0 +1,37 @@
+package shared
+
+import (
+	"encoding/binary"
+
+	"github.com/spacemeshos/sha256-simd"
+)
+
+// Difficulty returns the maximum index-hash value that still qualifies a
+// label for inclusion in a proof, given the K1 parameter and the total
+// number of labels: success = msb64(hash) <= maxTarget * (K1/numLabels).
+func Difficulty(k1 uint32, numLabels uint64) uint64 {
+	if numLabels == 0 {
+		return 0
+	}
+	maxTarget := ^uint64(0)
+	x := maxTarget / numLabels
+	y := maxTarget % numLabels
+	return x*uint64(k1) + (y*uint64(k1))/numLabels
+}
+
+// IndexHash hashes a label together with the challenge it's being checked
+// against, and returns the leading 64 bits as an integer so it can be
+// compared against Difficulty.
+func IndexHash(label, challenge []byte) uint64 {
+	h := sha256.New()
+	h.Write(label)
+	h.Write(challenge)
+	sum := h.Sum(nil)
+	return binary.BigEndian.Uint64(sum[:8])
+}
+
+// PassesDifficulty reports whether label qualifies under challenge for a
+// PoST instance with the given K1 and numLabels.
+func PassesDifficulty(label, challenge []byte, k1 uint32, numLabels uint64) bool {
+	return IndexHash(label, challenge) <= Difficulty(k1, numLabels)
+}
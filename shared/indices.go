@@ -0,0 +1,38 @@
+package shared
+
+// PackIndices tightly bit-packs indices into a byte slice, bitSize bits per
+// index, most significant bit first.
+func PackIndices(indices []uint64, bitSize uint) []byte {
+	out := make([]byte, Size(bitSize, uint(len(indices))))
+
+	var bitPos uint
+	for _, idx := range indices {
+		for b := int(bitSize) - 1; b >= 0; b-- {
+			if idx&(1<<uint(b)) != 0 {
+				out[bitPos/8] |= 1 << (7 - bitPos%8)
+			}
+			bitPos++
+		}
+	}
+	return out
+}
+
+// UnpackIndices reverses PackIndices, reading count indices of bitSize bits
+// each from data.
+func UnpackIndices(data []byte, bitSize uint, count int) []uint64 {
+	out := make([]uint64, count)
+
+	var bitPos uint
+	for i := 0; i < count; i++ {
+		var v uint64
+		for b := 0; b < int(bitSize); b++ {
+			v <<= 1
+			if data[bitPos/8]&(1<<(7-bitPos%8)) != 0 {
+				v |= 1
+			}
+			bitPos++
+		}
+		out[i] = v
+	}
+	return out
+}
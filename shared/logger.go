@@ -0,0 +1,16 @@
+package shared
+
+// Logger is the logging interface accepted throughout the post module.
+// Callers can plug in whatever structured or unstructured logger they
+// already use by implementing these two methods.
+type Logger interface {
+	Info(msg string, args ...interface{})
+	Debug(msg string, args ...interface{})
+}
+
+// DisabledLogger discards everything logged to it. It's the default used
+// where no Logger has been configured.
+type DisabledLogger struct{}
+
+func (DisabledLogger) Info(msg string, args ...interface{})  {}
+func (DisabledLogger) Debug(msg string, args ...interface{}) {}
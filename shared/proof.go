@@ -0,0 +1,54 @@
+package shared
+
+import "github.com/spacemeshos/post/vdf"
+
+// Proof is the result of a single proving pass: a compact demonstration
+// that the prover holds K2 labels, among those selected by Challenge, that
+// satisfy the K1/NumLabels difficulty requirement.
+type Proof struct {
+	// Indices packs the K2 selected label indices, BinaryRepresentationMinBits(NumLabels)
+	// bits each, tightly bit-packed in index order.
+	Indices []byte
+
+	// Labels holds the claimed label bytes for each selected index, in the
+	// same order as Indices.
+	Labels [][]byte
+
+	// MerklePaths[i] and MerklePathIndices[i] are the Merkle authentication
+	// path (sibling hashes) and left/right flags (0 = left, 1 = right, one
+	// per tree level) tying Labels[i] back to ProofMetaData.MerkleRoot.
+	MerklePaths       [][][]byte
+	MerklePathIndices [][]int64
+}
+
+// ProofMetaData carries everything a verifier needs to check a Proof
+// without access to the prover's data directory.
+type ProofMetaData struct {
+	Commitment []byte
+	Challenge  Challenge
+
+	BitsPerLabel  uint
+	LabelsPerUnit uint64
+	NumUnits      uint
+	K1            uint32
+	K2            uint32
+
+	// MerkleRoot is the root of the Merkle tree built over every label
+	// committed to during initialization.
+	MerkleRoot []byte
+
+	// VDFProof is the Wesolowski proof that Challenge was gated through
+	// VDFIterations sequential class-group squarings before being used to
+	// select labels, i.e. that the challenge actually used for selection
+	// is vdf.Generate's y rather than Challenge itself. Nil when the
+	// proof wasn't produced with VDF gating (config.Config.VDFEnabled
+	// false).
+	VDFProof *vdf.Proof
+
+	// VDFIterations and VDFDiscriminantBits are the VDF parameters
+	// VDFProof was produced under, duplicated here (mirroring K1/K2
+	// above) so a verifier never needs the prover's config.Config to
+	// check it.
+	VDFIterations       uint64
+	VDFDiscriminantBits int
+}
@@ -0,0 +1,268 @@
+// Package vdf implements a Wesolowski verifiable delay function over the
+// class group of an imaginary quadratic order of unknown order, following
+// the construction in Wesolowski, "Efficient Verifiable Delay Functions"
+// (2019) and the class-group instantiation popularized by Chia's VDF
+// competition.
+//
+// Evaluating the VDF for T squarings is inherently sequential, but Verify
+// runs in O(log T): it never repeats the squaring loop, only a cheap
+// exponentiation by a short Fiat-Shamir prime.
+package vdf
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+)
+
+// smallPrimes is the set of candidate leading coefficients tried when
+// deriving a generator form for a discriminant; the first one for which a
+// valid b exists is used.
+var smallPrimes = []int64{2, 3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37, 41, 43, 47, 53, 59, 61, 67, 71}
+
+// CreateDiscriminant deterministically derives a negative, bits-bit
+// discriminant D ≡ 1 (mod 4) with |D| prime from seed. Every caller that
+// passes the same seed and bits gets the same D, which is what lets a
+// prover and a verifier independently agree on the class group without
+// either one choosing (and so potentially knowing a trapdoor for) it.
+func CreateDiscriminant(seed []byte, bits int) *big.Int {
+	need := (bits + 7) / 8
+	for counter := uint64(0); ; counter++ {
+		buf := make([]byte, 0, need)
+		for block := uint64(0); len(buf) < need; block++ {
+			h := sha256.New()
+			h.Write(seed)
+			var cb [8]byte
+			binary.LittleEndian.PutUint64(cb[:], counter)
+			h.Write(cb[:])
+			binary.LittleEndian.PutUint64(cb[:], block)
+			h.Write(cb[:])
+			buf = append(buf, h.Sum(nil)...)
+		}
+		r := new(big.Int).SetBytes(buf[:need])
+		r.Rsh(r, uint(need*8-bits)) // drop the bits beyond bits
+		r.SetBit(r, bits-1, 1)      // force the exact bit length
+
+		if mod4 := new(big.Int).Mod(r, four); mod4.Cmp(big.NewInt(3)) != 0 {
+			r.Add(r, new(big.Int).Sub(big.NewInt(3), mod4))
+		}
+		if r.ProbablyPrime(30) {
+			return r.Neg(r)
+		}
+	}
+}
+
+// generator returns a reduced, non-identity form of discriminant d, used as
+// the VDF's base element x.
+func generator(d *big.Int) (*form, error) {
+	fourD := new(big.Int).Mod(d, big.NewInt(8))
+	for _, a := range smallPrimes {
+		ab := big.NewInt(a)
+		fourA := new(big.Int).Mul(big.NewInt(4), ab)
+
+		var b0 *big.Int
+		if a == 2 {
+			if fourD.Cmp(big.NewInt(1)) != 0 {
+				continue
+			}
+			b0 = big.NewInt(1)
+		} else {
+			dModA := new(big.Int).Mod(d, ab)
+			b0 = new(big.Int).ModSqrt(dModA, ab)
+			if b0 == nil {
+				continue
+			}
+		}
+
+		for j := int64(0); j < 4; j++ {
+			b := new(big.Int).Add(b0, new(big.Int).Mul(ab, big.NewInt(j)))
+			b.Mod(b, fourA)
+			if b.Bit(0) == 0 {
+				continue
+			}
+			rem := new(big.Int).Mul(b, b)
+			rem.Sub(rem, d)
+			rem.Mod(rem, fourA)
+			if rem.Sign() != 0 {
+				continue
+			}
+			c := new(big.Int).Mul(b, b)
+			c.Sub(c, d)
+			c.Div(c, fourA)
+			return reduce(&form{a: ab, b: b, c: c}), nil
+		}
+	}
+	return nil, fmt.Errorf("vdf: no generator found among the first %d candidate primes", len(smallPrimes))
+}
+
+// Config carries the VDF gating parameters for a proof.
+type Config struct {
+	// Iterations is T, the number of sequential squarings the prover must
+	// perform before a proof can be produced.
+	Iterations uint64
+
+	// DiscriminantBits is the bit length of the class group discriminant.
+	// Larger values increase the group's conjectured security margin at
+	// the cost of larger, slower arithmetic.
+	DiscriminantBits int
+}
+
+// Proof is a Wesolowski proof that Y = X^(2^Iterations) in the class group
+// of discriminant CreateDiscriminant(challenge, Config.DiscriminantBits).
+type Proof struct {
+	Y  []byte // reduced form (a,b,c) of the output, wire-encoded
+	Pi []byte // reduced form (a,b,c) of the proof element pi, wire-encoded
+}
+
+// Generate runs the VDF on challenge for cfg.Iterations sequential
+// squarings and returns the gated challenge y = VDF(challenge, T) together
+// with a proof that it was computed correctly. It's cancellable via ctx,
+// since cfg.Iterations can represent minutes or hours of wall-clock work.
+func Generate(ctx context.Context, challenge []byte, cfg Config) (y []byte, proof *Proof, err error) {
+	d := CreateDiscriminant(challenge, cfg.DiscriminantBits)
+	x, err := generator(d)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cur := x
+	for i := uint64(0); i < cfg.Iterations; i++ {
+		if i%1024 == 0 {
+			select {
+			case <-ctx.Done():
+				return nil, nil, ctx.Err()
+			default:
+			}
+		}
+		cur = square(cur, d)
+	}
+	yForm := cur
+
+	l := hashToPrime(x, yForm, cfg.Iterations)
+	pi, err := wesolowskiProof(ctx, x, cfg.Iterations, l, d)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return encodeForm(yForm), &Proof{Y: encodeForm(yForm), Pi: encodeForm(pi)}, nil
+}
+
+// wesolowskiProof computes pi = x^q, where q = floor(2^iterations / l),
+// without ever materializing 2^iterations: it derives the quotient's bits
+// one at a time by long division, squaring pi once per bit, following
+// Wesolowski's original long-division trick. This is a second pass of
+// iterations squarings, run after Generate's evaluation loop, and it checks
+// ctx at the same interval.
+func wesolowskiProof(ctx context.Context, x *form, iterations uint64, l *big.Int, d *big.Int) (*form, error) {
+	pi := identityForm(d)
+	r := big.NewInt(1)
+
+	for i := uint64(0); i < iterations; i++ {
+		if i%1024 == 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			default:
+			}
+		}
+		r.Mul(r, two)
+		b := new(big.Int).Div(r, l)
+		r.Mod(r, l)
+
+		pi = square(pi, d)
+		if b.Sign() != 0 {
+			pi = multiply(pi, x, d)
+		}
+	}
+	return pi, nil
+}
+
+// Verify checks proof against challenge and cfg without repeating the
+// sequential squaring: it recomputes the Fiat-Shamir prime l and the short
+// exponent r = 2^iterations mod l, then checks pi^l * x^r == y.
+func Verify(challenge []byte, cfg Config, proof *Proof) (bool, error) {
+	d := CreateDiscriminant(challenge, cfg.DiscriminantBits)
+	x, err := generator(d)
+	if err != nil {
+		return false, err
+	}
+
+	y, err := decodeForm(proof.Y)
+	if err != nil {
+		return false, fmt.Errorf("vdf: decoding y: %w", err)
+	}
+	pi, err := decodeForm(proof.Pi)
+	if err != nil {
+		return false, fmt.Errorf("vdf: decoding pi: %w", err)
+	}
+
+	l := hashToPrime(x, y, cfg.Iterations)
+
+	exp := new(big.Int).SetUint64(cfg.Iterations)
+	r := new(big.Int).Exp(two, exp, l)
+
+	lhs := multiply(pow(pi, l, d), pow(x, r, d), d)
+	return lhs.equal(y), nil
+}
+
+// hashToPrime derives l via Fiat-Shamir over (x, y, iterations): hash the
+// triple with an incrementing counter until the result, interpreted as an
+// odd integer, is probably prime.
+func hashToPrime(x, y *form, iterations uint64) *big.Int {
+	for counter := uint64(0); ; counter++ {
+		h := sha256.New()
+		h.Write(encodeForm(x))
+		h.Write(encodeForm(y))
+		var ib [8]byte
+		binary.LittleEndian.PutUint64(ib[:], iterations)
+		h.Write(ib[:])
+		binary.LittleEndian.PutUint64(ib[:], counter)
+		h.Write(ib[:])
+
+		l := new(big.Int).SetBytes(h.Sum(nil))
+		l.SetBit(l, 0, 1) // odd
+		if l.ProbablyPrime(30) {
+			return l
+		}
+	}
+}
+
+func encodeForm(f *form) []byte {
+	var buf []byte
+	for _, n := range []*big.Int{f.a, f.b, f.c} {
+		b := n.Bytes()
+		var lenBuf [4]byte
+		binary.LittleEndian.PutUint32(lenBuf[:], uint32(len(b)))
+		buf = append(buf, lenBuf[:]...)
+		buf = append(buf, b...)
+		if n.Sign() < 0 {
+			buf = append(buf, 1)
+		} else {
+			buf = append(buf, 0)
+		}
+	}
+	return buf
+}
+
+func decodeForm(data []byte) (*form, error) {
+	vals := make([]*big.Int, 0, 3)
+	for i := 0; i < 3; i++ {
+		if len(data) < 4 {
+			return nil, fmt.Errorf("vdf: truncated form encoding")
+		}
+		n := binary.LittleEndian.Uint32(data[:4])
+		data = data[4:]
+		if uint32(len(data)) < n+1 {
+			return nil, fmt.Errorf("vdf: truncated form encoding")
+		}
+		v := new(big.Int).SetBytes(data[:n])
+		if data[n] == 1 {
+			v.Neg(v)
+		}
+		data = data[n+1:]
+		vals = append(vals, v)
+	}
+	return &form{a: vals[0], b: vals[1], c: vals[2]}, nil
+}
@@ -0,0 +1,63 @@
+package vdf
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateVerify(t *testing.T) {
+	r := require.New(t)
+
+	cfg := Config{Iterations: 200, DiscriminantBits: 256}
+	challenge := []byte("post-vdf-test-challenge")
+
+	y, proof, err := Generate(context.Background(), challenge, cfg)
+	r.NoError(err)
+	r.NotEmpty(y)
+	r.NotNil(proof)
+
+	ok, err := Verify(challenge, cfg, proof)
+	r.NoError(err)
+	r.True(ok)
+}
+
+func TestVerify_WrongChallenge(t *testing.T) {
+	r := require.New(t)
+
+	cfg := Config{Iterations: 200, DiscriminantBits: 256}
+	_, proof, err := Generate(context.Background(), []byte("challenge-a"), cfg)
+	r.NoError(err)
+
+	ok, err := Verify([]byte("challenge-b"), cfg, proof)
+	r.NoError(err)
+	r.False(ok)
+}
+
+func TestVerify_TamperedOutput(t *testing.T) {
+	r := require.New(t)
+
+	cfg := Config{Iterations: 200, DiscriminantBits: 256}
+	challenge := []byte("post-vdf-test-challenge")
+	_, proof, err := Generate(context.Background(), challenge, cfg)
+	r.NoError(err)
+
+	otherY, _, err := Generate(context.Background(), []byte("different"), Config{Iterations: 200, DiscriminantBits: 256})
+	r.NoError(err)
+	proof.Y = otherY
+
+	ok, _ := Verify(challenge, cfg, proof)
+	r.False(ok)
+}
+
+func TestGenerate_ContextCancelled(t *testing.T) {
+	r := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cfg := Config{Iterations: 1 << 20, DiscriminantBits: 256}
+	_, _, err := Generate(ctx, []byte("post-vdf-test-challenge"), cfg)
+	r.Error(err)
+}
@@ -0,0 +1,189 @@
+package verifying
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+
+	"github.com/spacemeshos/post/shared"
+	"github.com/spacemeshos/post/vdf"
+)
+
+// difficultyKey identifies the (K1, numLabels) pair a proof's difficulty
+// threshold is computed from. Every item enqueued with the same key shares
+// the exact same threshold.
+type difficultyKey struct {
+	k1        uint32
+	numLabels uint64
+}
+
+// batchItem is a single (proof, metadata) pair enqueued on a BatchVerifier,
+// along with its original position so a failure can be reported against
+// its Enqueue index.
+type batchItem struct {
+	index         int
+	proof         *shared.Proof
+	proofMetaData *shared.ProofMetaData
+}
+
+// BatchVerifier amortizes the per-proof verification cost of many
+// independent PoST proofs by spreading their K2 label checks across a
+// fixed worker pool and sharing per-(K1, numLabels) sub-computation (the
+// difficulty threshold) across items, in the same spirit as the batched
+// signature verifiers used elsewhere in the blockchain ecosystem (e.g.
+// go-algorand's batch Ed25519 verifier). Label hashing itself
+// (shared.IndexHash) and Merkle-path recomputation are unchanged from
+// single-proof verification - both still hash one label at a time - so the
+// saving here is strictly from parallelism and shared sub-computation, not
+// from any interleaved or multi-lane hashing.
+//
+// Every enqueued proof is still checked individually and in full: batching
+// only shares per-(K1, numLabels) sub-computation (the difficulty
+// threshold) across items and spreads the K2 label checks across a fixed
+// worker pool. It never skips or merges whole items, even when two items
+// share a commitment and challenge - two proofs that look identical at that
+// granularity can still disagree on individual labels, and silently
+// trusting one on behalf of the other would let a forged proof hide behind
+// a valid one.
+//
+// A BatchVerifier is not safe for concurrent use; each goroutine should use
+// its own instance.
+type BatchVerifier struct {
+	items []batchItem
+}
+
+// NewBatchVerifier returns an empty BatchVerifier.
+func NewBatchVerifier() *BatchVerifier {
+	return &BatchVerifier{}
+}
+
+// Enqueue adds a (proof, proofMetaData) pair to the batch. It returns the
+// index that will identify this pair in the error slice returned by Verify.
+func (b *BatchVerifier) Enqueue(proof *shared.Proof, proofMetaData *shared.ProofMetaData) int {
+	index := len(b.items)
+	b.items = append(b.items, batchItem{index: index, proof: proof, proofMetaData: proofMetaData})
+	return index
+}
+
+// Verify checks every proof enqueued so far. If all of them are valid, it
+// returns nil. Otherwise it returns a slice of *VerifyError, one per failing
+// item, each identifying its Enqueue index.
+func (b *BatchVerifier) Verify(ctx context.Context) []error {
+	if len(b.items) == 0 {
+		return nil
+	}
+
+	// Precompute each distinct (K1, numLabels) pair's difficulty threshold
+	// once, up front, so the worker pool below never redoes that division
+	// for items that share it.
+	difficulties := make(map[difficultyKey]uint64)
+	for _, it := range b.items {
+		numLabels := it.proofMetaData.LabelsPerUnit * uint64(it.proofMetaData.NumUnits)
+		key := difficultyKey{k1: it.proofMetaData.K1, numLabels: numLabels}
+		if _, ok := difficulties[key]; !ok {
+			difficulties[key] = shared.Difficulty(key.k1, numLabels)
+		}
+	}
+
+	numWorkers := runtime.NumCPU()
+	jobs := make(chan batchItem, len(b.items))
+	results := make(chan error, len(b.items))
+
+	var wg sync.WaitGroup
+	wg.Add(numWorkers)
+	for i := 0; i < numWorkers; i++ {
+		go func() {
+			defer wg.Done()
+			b.worker(ctx, jobs, results, difficulties)
+		}()
+	}
+
+	for _, it := range b.items {
+		jobs <- it
+	}
+	close(jobs)
+
+	wg.Wait()
+	close(results)
+
+	var errs []error
+	for err := range results {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+func (b *BatchVerifier) worker(ctx context.Context, jobs <-chan batchItem, results chan<- error, difficulties map[difficultyKey]uint64) {
+	for it := range jobs {
+		select {
+		case <-ctx.Done():
+			results <- &VerifyError{Index: it.index, Err: ctx.Err()}
+			continue
+		default:
+		}
+
+		numLabels := it.proofMetaData.LabelsPerUnit * uint64(it.proofMetaData.NumUnits)
+		key := difficultyKey{k1: it.proofMetaData.K1, numLabels: numLabels}
+		k2 := int(it.proofMetaData.K2)
+		if len(it.proof.Labels) != k2 || len(it.proof.MerklePaths) != k2 || len(it.proof.MerklePathIndices) != k2 {
+			results <- &VerifyError{Index: it.index, Err: fmt.Errorf(
+				"verifying: expected %d labels, got %d labels, %d merkle paths, %d path indices",
+				k2, len(it.proof.Labels), len(it.proof.MerklePaths), len(it.proof.MerklePathIndices))}
+			continue
+		}
+
+		selectionChallenge := []byte(it.proofMetaData.Challenge)
+		if it.proofMetaData.VDFProof != nil {
+			vdfCfg := vdf.Config{Iterations: it.proofMetaData.VDFIterations, DiscriminantBits: it.proofMetaData.VDFDiscriminantBits}
+			ok, err := vdf.Verify(it.proofMetaData.Challenge, vdfCfg, it.proofMetaData.VDFProof)
+			if err != nil {
+				results <- &VerifyError{Index: it.index, Err: fmt.Errorf("verifying: VDF gate: %w", err)}
+				continue
+			}
+			if !ok {
+				results <- &VerifyError{Index: it.index, Err: fmt.Errorf("verifying: VDF gate: proof does not verify against the challenge")}
+				continue
+			}
+			selectionChallenge = it.proofMetaData.VDFProof.Y
+		}
+
+		if err := verify(it.proof, it.proofMetaData, selectionChallenge, k2, numLabels, difficulties[key]); err != nil {
+			results <- &VerifyError{Index: it.index, Err: err}
+			continue
+		}
+		results <- nil
+	}
+}
+
+// VerifyError reports that the proof enqueued at Index failed verification.
+type VerifyError struct {
+	Index int
+	Err   error
+}
+
+func (e *VerifyError) Error() string {
+	return fmt.Sprintf("proof at index %d: %v", e.Index, e.Err)
+}
+
+func (e *VerifyError) Unwrap() error {
+	return e.Err
+}
+
+// AsyncVerify is equivalent to Verify, except it returns immediately and
+// reports the result on the returned channel once available. This lets a
+// caller pipeline verification alongside network I/O instead of blocking on
+// it.
+func (b *BatchVerifier) AsyncVerify(ctx context.Context) <-chan []error {
+	out := make(chan []error, 1)
+	go func() {
+		out <- b.Verify(ctx)
+		close(out)
+	}()
+	return out
+}
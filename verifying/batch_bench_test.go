@@ -0,0 +1,100 @@
+package verifying
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"github.com/spacemeshos/post/config"
+	"github.com/spacemeshos/post/initialization"
+	"github.com/spacemeshos/post/proving"
+	"github.com/spacemeshos/post/shared"
+)
+
+// buildProofs initializes a single PoST data directory and generates n
+// proofs against it, one per distinct challenge, for use as benchmark
+// fixtures.
+func buildProofs(b *testing.B, n int) ([]*shared.Proof, []*shared.ProofMetaData) {
+	b.Helper()
+
+	cfg := config.DefaultConfig()
+	cfg.LabelsPerUnit = 1 << 12
+
+	opts := config.DefaultInitOpts()
+	opts.DataDir = b.TempDir()
+	opts.NumUnits = cfg.MinNumUnits
+	opts.NumFiles = 1
+	opts.ComputeProviderID = initialization.CPUProviderID()
+
+	commitment := make([]byte, 32)
+
+	init, err := initialization.NewInitializer(
+		initialization.WithCommitment(commitment),
+		initialization.WithConfig(cfg),
+		initialization.WithInitOpts(opts),
+	)
+	if err != nil {
+		b.Fatal(err)
+	}
+	if err := init.Initialize(context.Background()); err != nil {
+		b.Fatal(err)
+	}
+
+	p, err := proving.NewProver(cfg, opts.DataDir, commitment)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	proofs := make([]*shared.Proof, n)
+	metas := make([]*shared.ProofMetaData, n)
+	for i := 0; i < n; i++ {
+		ch := make(proving.Challenge, 32)
+		binary.BigEndian.PutUint64(ch, uint64(i))
+
+		proof, meta, err := p.GenerateProof(ch)
+		if err != nil {
+			b.Fatal(err)
+		}
+		proofs[i] = proof
+		metas[i] = meta
+	}
+	return proofs, metas
+}
+
+func benchmarkSequentialVerify(b *testing.B, batchSize int) {
+	proofs, metas := buildProofs(b, batchSize)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for j := range proofs {
+			if err := Verify(proofs[j], metas[j]); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func benchmarkBatchVerify(b *testing.B, batchSize int) {
+	proofs, metas := buildProofs(b, batchSize)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		bv := NewBatchVerifier()
+		for j := range proofs {
+			bv.Enqueue(proofs[j], metas[j])
+		}
+		if errs := bv.Verify(context.Background()); errs != nil {
+			b.Fatal(errs)
+		}
+	}
+}
+
+func BenchmarkVerify_Sequential_1(b *testing.B)   { benchmarkSequentialVerify(b, 1) }
+func BenchmarkVerify_Sequential_8(b *testing.B)   { benchmarkSequentialVerify(b, 8) }
+func BenchmarkVerify_Sequential_64(b *testing.B)  { benchmarkSequentialVerify(b, 64) }
+func BenchmarkVerify_Sequential_512(b *testing.B) { benchmarkSequentialVerify(b, 512) }
+
+func BenchmarkBatchVerifier_1(b *testing.B)   { benchmarkBatchVerify(b, 1) }
+func BenchmarkBatchVerifier_8(b *testing.B)   { benchmarkBatchVerify(b, 8) }
+func BenchmarkBatchVerifier_64(b *testing.B)  { benchmarkBatchVerify(b, 64) }
+func BenchmarkBatchVerifier_512(b *testing.B) { benchmarkBatchVerify(b, 512) }
@@ -0,0 +1,70 @@
+package verifying
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/post/config"
+	"github.com/spacemeshos/post/initialization"
+	"github.com/spacemeshos/post/proving"
+	"github.com/spacemeshos/post/shared"
+)
+
+// TestBatchVerifier_ReportsForgedProofIndex reproduces a forged proof that
+// shares its commitment and challenge with a valid one already in the
+// batch - the one case a whole-item dedup keyed on (commitment, challenge)
+// would wrongly treat as redundant and skip - and checks that the forged
+// proof is still verified and its Enqueue index is reported as failing.
+func TestBatchVerifier_ReportsForgedProofIndex(t *testing.T) {
+	r := require.New(t)
+
+	cfg := config.DefaultConfig()
+	cfg.LabelsPerUnit = 1 << 12
+
+	opts := config.DefaultInitOpts()
+	opts.DataDir = t.TempDir()
+	opts.NumUnits = cfg.MinNumUnits
+	opts.NumFiles = 1
+	opts.ComputeProviderID = initialization.CPUProviderID()
+
+	commitment := make([]byte, 32)
+
+	init, err := initialization.NewInitializer(
+		initialization.WithCommitment(commitment),
+		initialization.WithConfig(cfg),
+		initialization.WithInitOpts(opts),
+	)
+	r.NoError(err)
+	r.NoError(init.Initialize(context.Background()))
+
+	p, err := proving.NewProver(cfg, opts.DataDir, commitment)
+	r.NoError(err)
+
+	ch := make(proving.Challenge, 32)
+	validProof, meta, err := p.GenerateProof(ch)
+	r.NoError(err)
+
+	forgedProof := &shared.Proof{
+		Indices:           validProof.Indices,
+		Labels:            append([][]byte{}, validProof.Labels...),
+		MerklePaths:       validProof.MerklePaths,
+		MerklePathIndices: validProof.MerklePathIndices,
+	}
+	forgedProof.Labels[0] = append([]byte{}, forgedProof.Labels[0]...)
+	forgedProof.Labels[0][0] ^= 0xff
+
+	bv := NewBatchVerifier()
+	validIndex := bv.Enqueue(validProof, meta)
+	forgedIndex := bv.Enqueue(forgedProof, meta)
+
+	errs := bv.Verify(context.Background())
+	r.Len(errs, 1)
+
+	var verr *VerifyError
+	r.True(errors.As(errs[0], &verr))
+	r.Equal(forgedIndex, verr.Index)
+	r.NotEqual(validIndex, verr.Index)
+}
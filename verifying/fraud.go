@@ -0,0 +1,63 @@
+package verifying
+
+import (
+	"fmt"
+
+	"github.com/spacemeshos/post/fraud"
+	"github.com/spacemeshos/post/shared"
+)
+
+// FraudError wraps a verification failure that Verify was able to package
+// into a compact, independently checkable fraud.Proof. Callers that need to
+// hand off the disputed index to a third party - rather than just reject
+// the proof locally - can use errors.As to retrieve it.
+type FraudError struct {
+	Err   error
+	Proof *fraud.Proof
+}
+
+func (e *FraudError) Error() string {
+	return fmt.Sprintf("verifying: %v (fraud proof: reason=%s index=%d)", e.Err, e.Proof.Reason, e.Proof.Index)
+}
+
+func (e *FraudError) Unwrap() error {
+	return e.Err
+}
+
+// badLabelFraudProof builds a fraud.Proof asserting that the committed
+// label at index isn't the label expected under meta.Commitment.
+func badLabelFraudProof(meta *shared.ProofMetaData, index uint64, claimed, expected []byte, path [][]byte, pathIndex []int64) *fraud.Proof {
+	return &fraud.Proof{
+		Commitment:        meta.Commitment,
+		Challenge:         meta.Challenge,
+		Reason:            fraud.BadLabel,
+		Index:             index,
+		ClaimedLabel:      claimed,
+		ExpectedLabel:     expected,
+		MerklePath:        path,
+		MerklePathIndices: pathIndex,
+		Root:              meta.MerkleRoot,
+	}
+}
+
+// badIndexFraudProof builds a fraud.Proof asserting that the committed
+// label at index fails the K1/numLabels difficulty requirement it was
+// supposedly selected under. selectionChallenge is what the label was
+// actually hashed against to check that - proofMetaData.Challenge gated
+// through the VDF when proofMetaData.VDFProof is set, else
+// proofMetaData.Challenge itself - since that's what fraud.Verify must
+// redo shared.IndexHash against to independently reproduce the claim.
+func badIndexFraudProof(meta *shared.ProofMetaData, numLabels uint64, index uint64, claimed []byte, path [][]byte, pathIndex []int64, selectionChallenge []byte) *fraud.Proof {
+	return &fraud.Proof{
+		Commitment:        meta.Commitment,
+		Challenge:         selectionChallenge,
+		Reason:            fraud.BadIndex,
+		Index:             index,
+		ClaimedLabel:      claimed,
+		MerklePath:        path,
+		MerklePathIndices: pathIndex,
+		Root:              meta.MerkleRoot,
+		K1:                meta.K1,
+		NumLabels:         numLabels,
+	}
+}
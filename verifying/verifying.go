@@ -0,0 +1,122 @@
+// Package verifying checks PoST proofs produced by the proving package,
+// without needing access to the prover's data directory.
+package verifying
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/spacemeshos/post/initialization"
+	"github.com/spacemeshos/post/shared"
+	"github.com/spacemeshos/post/vdf"
+)
+
+// Verify checks that proof demonstrates K2 labels, selected under the
+// proof's selection challenge, that are both authenticated against
+// proofMetaData.MerkleRoot and individually satisfy the K1/NumLabels
+// difficulty requirement. The selection challenge is proofMetaData.Challenge
+// itself, unless proofMetaData.VDFProof is set, in which case it's that
+// proof's gated output - checked first, since there's no point doing the
+// far more expensive Merkle-path and index-hash work for labels selected
+// under a challenge nobody can show was derived correctly.
+func Verify(proof *shared.Proof, proofMetaData *shared.ProofMetaData) error {
+	k2 := int(proofMetaData.K2)
+	if len(proof.Labels) != k2 || len(proof.MerklePaths) != k2 || len(proof.MerklePathIndices) != k2 {
+		return fmt.Errorf("verifying: expected %d labels, got %d labels, %d merkle paths, %d path indices",
+			k2, len(proof.Labels), len(proof.MerklePaths), len(proof.MerklePathIndices))
+	}
+
+	selectionChallenge := []byte(proofMetaData.Challenge)
+	if proofMetaData.VDFProof != nil {
+		vdfCfg := vdf.Config{Iterations: proofMetaData.VDFIterations, DiscriminantBits: proofMetaData.VDFDiscriminantBits}
+		ok, err := vdf.Verify(proofMetaData.Challenge, vdfCfg, proofMetaData.VDFProof)
+		if err != nil {
+			return fmt.Errorf("verifying: VDF gate: %w", err)
+		}
+		if !ok {
+			return fmt.Errorf("verifying: VDF gate: proof does not verify against the challenge")
+		}
+		selectionChallenge = proofMetaData.VDFProof.Y
+	}
+
+	numLabels := proofMetaData.LabelsPerUnit * uint64(proofMetaData.NumUnits)
+	difficulty := shared.Difficulty(proofMetaData.K1, numLabels)
+	return verify(proof, proofMetaData, selectionChallenge, k2, numLabels, difficulty)
+}
+
+// verify is Verify's implementation, parameterized on the selection
+// challenge and difficulty threshold rather than deriving them from
+// proofMetaData. BatchVerifier calls this directly so that proofs sharing a
+// (K1, numLabels) pair - the common case when a batch comes from a single
+// PoST config - pay for shared.Difficulty's division once per pair instead
+// of once per proof.
+func verify(proof *shared.Proof, proofMetaData *shared.ProofMetaData, selectionChallenge []byte, k2 int, numLabels uint64, difficulty uint64) error {
+	indexBitSize := shared.BinaryRepresentationMinBits(numLabels)
+	if got := shared.Size(indexBitSize, uint(k2)); got != uint(len(proof.Indices)) {
+		return fmt.Errorf("verifying: expected %d bytes of indices, got %d", got, len(proof.Indices))
+	}
+	indices := shared.UnpackIndices(proof.Indices, indexBitSize, k2)
+
+	for i := 0; i < k2; i++ {
+		label := proof.Labels[i]
+		index := indices[i]
+
+		root, err := recomputeRoot(label, proof.MerklePaths[i], proof.MerklePathIndices[i])
+		if err != nil {
+			return fmt.Errorf("verifying: index %d: %w", index, err)
+		}
+		if !bytes.Equal(root, proofMetaData.MerkleRoot) {
+			return fmt.Errorf("verifying: index %d: label does not authenticate against the committed merkle root", index)
+		}
+
+		// The label does authenticate against the root, so it's genuinely
+		// what the prover committed to. Check it's what the prover should
+		// have committed to, and that it was eligible for selection - both
+		// checks a third party can redo from nothing but a fraud.Proof.
+		expected := initialization.ComputeLabel(proofMetaData.Commitment, index, proofMetaData.BitsPerLabel)
+		if !bytes.Equal(label, expected) {
+			return &FraudError{
+				Err:   fmt.Errorf("verifying: index %d: committed label does not match the label expected under the commitment", index),
+				Proof: badLabelFraudProof(proofMetaData, index, label, expected, proof.MerklePaths[i], proof.MerklePathIndices[i]),
+			}
+		}
+
+		if shared.IndexHash(label, selectionChallenge) > difficulty {
+			return &FraudError{
+				Err:   fmt.Errorf("verifying: index %d: label does not satisfy the K1/NumLabels difficulty requirement", index),
+				Proof: badIndexFraudProof(proofMetaData, numLabels, index, label, proof.MerklePaths[i], proof.MerklePathIndices[i], selectionChallenge),
+			}
+		}
+	}
+
+	return nil
+}
+
+// recomputeRoot walks a Merkle authentication path from label's leaf hash
+// up to the root, using the same left/right convention and sha256 combine
+// function as github.com/cbergoon/merkletree.
+func recomputeRoot(label []byte, path [][]byte, pathIndex []int64) ([]byte, error) {
+	if len(path) != len(pathIndex) {
+		return nil, fmt.Errorf("verifying: merkle path has %d siblings but %d indices", len(path), len(pathIndex))
+	}
+
+	h := sha256.Sum256(label)
+	hash := h[:]
+
+	for i, sibling := range path {
+		combined := sha256.New()
+		// pathIndex[i] == 1 means the sibling is the right leaf (hash is
+		// left); 0 means the sibling is the left leaf (hash is right) -
+		// matching github.com/cbergoon/merkletree.GetMerklePath.
+		if pathIndex[i] == 1 {
+			combined.Write(hash)
+			combined.Write(sibling)
+		} else {
+			combined.Write(sibling)
+			combined.Write(hash)
+		}
+		hash = combined.Sum(nil)
+	}
+	return hash, nil
+}